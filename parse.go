@@ -0,0 +1,267 @@
+package secureenv
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseOptions configures the behavior of ParseWithOptions.
+type ParseOptions struct {
+	// Prefix is prepended to every variable name, including those of
+	// fields in nested structs, ahead of any envPrefix those carry.
+	Prefix string
+
+	// Secure makes Parse read every field with SecureGetString instead
+	// of GetString, so matched environment entries are scrubbed in
+	// place rather than merely unset. See SecureGetString for caveats.
+	Secure bool
+}
+
+// MissingEnvError is returned by Parse and ParseWithOptions when one or
+// more fields tagged envRequired could not be resolved. It reports every
+// missing key at once instead of failing on the first one.
+type MissingEnvError struct {
+	Keys []string
+}
+
+func (e *MissingEnvError) Error() string {
+	return fmt.Sprintf("secureenv: missing required environment variable(s): %s", strings.Join(e.Keys, ", "))
+}
+
+var expandPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// Parse walks v, which must be a pointer to a struct, and populates its
+// fields from environment variables using struct tags, unsetting each
+// variable it consumes exactly as the GetString/GetInt/etc. helpers do.
+//
+// Supported tags:
+//
+//	env          name of the environment variable to read
+//	envDefault   value used when the variable is not set
+//	envSeparator separator used to split slice values, default ","
+//	envRequired  if "true", a missing variable is a reported error
+//	envExpand    if "true", "${OTHER}" references in the value are
+//	             expanded (and the referenced variables unset) before
+//	             the field is parsed
+//	envPrefix    on a nested/embedded struct field, prepended to every
+//	             tag of that struct's fields
+//
+// Supported field types are the existing scalars (string, bool, the
+// float/int/uint family), time.Duration, *url.URL, net.IP, slices of any
+// of those scalars, map[string]string, and nested/embedded structs.
+func Parse(v interface{}) error {
+	return ParseWithOptions(v, ParseOptions{})
+}
+
+// ParseWithOptions is like Parse but lets the caller set a Prefix applied
+// to every field, nested ones included.
+func ParseWithOptions(v interface{}, opts ParseOptions) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("secureenv: Parse expects a pointer to struct, got %T", v)
+	}
+
+	var missing []string
+	if err := parseStruct(rv.Elem(), opts.Prefix, opts.Secure, &missing); err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return &MissingEnvError{Keys: missing}
+	}
+	return nil
+}
+
+func parseStruct(sv reflect.Value, prefix string, secure bool, missing *[]string) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		fv := sv.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue // unexported
+		}
+
+		if childPrefix, ok := sf.Tag.Lookup("envPrefix"); ok || isPlainStruct(fv) {
+			target := fv
+			if target.Kind() == reflect.Ptr {
+				if target.IsNil() {
+					target.Set(reflect.New(target.Type().Elem()))
+				}
+				target = target.Elem()
+			}
+			if target.Kind() == reflect.Struct && !isScalarStructType(target.Type()) {
+				if err := parseStruct(target, prefix+childPrefix, secure, missing); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		name, ok := sf.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		key := prefix + name
+
+		get := GetString
+		if secure {
+			get = SecureGetString
+		}
+		raw, present := get(key)
+		if !present {
+			if def, ok := sf.Tag.Lookup("envDefault"); ok {
+				raw, present = def, true
+			}
+		}
+		if sf.Tag.Get("envExpand") == "true" && present {
+			raw = expandPattern.ReplaceAllStringFunc(raw, func(m string) string {
+				name := expandPattern.FindStringSubmatch(m)[1]
+				if val, ok := get(name); ok {
+					return val
+				}
+				return ""
+			})
+		}
+		if !present {
+			if sf.Tag.Get("envRequired") == "true" {
+				*missing = append(*missing, key)
+			}
+			continue
+		}
+
+		sep := sf.Tag.Get("envSeparator")
+		if sep == "" {
+			sep = ","
+		}
+		if err := setField(fv, raw, sep); err != nil {
+			return fmt.Errorf("secureenv: %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// isPlainStruct reports whether fv is an (optionally pointer to) struct
+// that should be recursed into even without an explicit envPrefix tag,
+// e.g. embedded structs.
+func isPlainStruct(fv reflect.Value) bool {
+	t := fv.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isScalarStructType(t) && fv.CanSet()
+}
+
+// isScalarStructType reports whether t is one of the struct-kinded types
+// this package parses as a single scalar value rather than recursing
+// into its fields. time.Duration and net.IP are not struct-kinded, so
+// they never need to be listed here; they're excluded from recursion by
+// isPlainStruct's Kind() check instead.
+func isScalarStructType(t reflect.Type) bool {
+	return t == reflect.TypeOf(url.URL{})
+}
+
+func setField(fv reflect.Value, raw, sep string) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setField(fv.Elem(), raw, sep)
+	}
+
+	// These checks must run before the Kind()-based dispatch below,
+	// since net.IP's Kind is Slice and would otherwise be mistaken for
+	// a plain scalar slice.
+	switch v := fv.Addr().Interface().(type) {
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		*v = d
+		return nil
+	case *url.URL:
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		*v = *u
+		return nil
+	case *net.IP:
+		ip := net.ParseIP(raw)
+		if ip == nil {
+			return fmt.Errorf("invalid IP %q", raw)
+		}
+		*v = ip
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Slice:
+		return setSlice(fv, raw, sep)
+	case reflect.Map:
+		return setMap(fv, raw, sep)
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 0, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 0, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
+
+func setSlice(fv reflect.Value, raw, sep string) error {
+	parts := strings.Split(raw, sep)
+	out := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+	for i, p := range parts {
+		if err := setField(out.Index(i), strings.TrimSpace(p), sep); err != nil {
+			return err
+		}
+	}
+	fv.Set(out)
+	return nil
+}
+
+func setMap(fv reflect.Value, raw, sep string) error {
+	out := reflect.MakeMap(fv.Type())
+	if raw == "" {
+		fv.Set(out)
+		return nil
+	}
+	for _, pair := range strings.Split(raw, sep) {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid map entry %q, expected k:v", pair)
+		}
+		out.SetMapIndex(reflect.ValueOf(strings.TrimSpace(kv[0])), reflect.ValueOf(strings.TrimSpace(kv[1])))
+	}
+	fv.Set(out)
+	return nil
+}
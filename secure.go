@@ -0,0 +1,80 @@
+package secureenv
+
+import (
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// SecureGetString is like GetString, but additionally attempts to
+// overwrite the backing bytes of the matched "KEY=value" entry with
+// zeros before unsetting the variable, so a later memory dump or
+// /proc/self/environ read is less likely to recover the secret.
+//
+// Platform caveats: on Unix, os.Environ and syscall.Environ both return
+// slices of Go strings that were copied from the C environ block once at
+// process startup and are reused afterwards; the string headers in those
+// slices still point at that same shared backing array, so overwriting
+// the bytes in place does scrub it. It does not reach a copy of the
+// environment a child process may already have inherited, and it cannot
+// undo a memory page already swapped or dumped before this call runs. On
+// Windows the environment block is not exposed as memory this package
+// can safely reach, so SecureGetString falls back to the same behavior
+// as GetString.
+func SecureGetString(key string) (output string, ok bool) {
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	output, ok = lookupEnvOrFile(key)
+	if !ok {
+		return output, ok
+	}
+	// output may alias the "KEY=value" entry's backing bytes (LookupEnv
+	// returns a substring of it), so it must be cloned onto its own
+	// memory before that entry is scrubbed, or the caller would be
+	// handed back the zeroed-out bytes instead of the secret.
+	out := strings.Clone(output)
+	// Scrub the backing bytes before unsetting: once Unsetenv removes
+	// the entry it no longer appears in os.Environ()/syscall.Environ(),
+	// so there would be nothing left to find and zero.
+	if runtime.GOOS != "windows" {
+		scrubEnvEntry(key, output)
+	}
+	os.Unsetenv(key)
+	return out, ok
+}
+
+// scrubEnvEntry zeroes the "key=value" entry for key, if still present in
+// os.Environ or syscall.Environ, in place.
+func scrubEnvEntry(key, value string) {
+	entry := key + "=" + value
+	scrubIn(environSnapshot(), entry)
+}
+
+// environSnapshot merges os- and syscall-level environment snapshots so
+// that whichever one still holds a live reference to the shared backing
+// array gets scrubbed.
+func environSnapshot() []string {
+	return append(append([]string{}, os.Environ()...), syscall.Environ()...)
+}
+
+func scrubIn(envs []string, entry string) {
+	for _, e := range envs {
+		if e == entry {
+			zeroString(e)
+		}
+	}
+}
+
+// zeroString overwrites the bytes backing s with zeros using unsafe. s
+// must not be read again after this call.
+func zeroString(s string) {
+	hdr := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	b := unsafe.Slice((*byte)(unsafe.Pointer(hdr.Data)), hdr.Len)
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,43 @@
+package secureenv
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSecureGetString(t *testing.T) {
+	const key = "SECUREENV_TEST_SECRET"
+	const secret = "supersecretvalue123"
+
+	os.Setenv(key, secret)
+
+	var before string
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, key+"=") {
+			before = e
+			break
+		}
+	}
+	if before == "" {
+		t.Fatalf("test setup: %s not found in os.Environ()", key)
+	}
+
+	got, ok := SecureGetString(key)
+	if !ok {
+		t.Fatalf("SecureGetString(%q) ok = false, want true", key)
+	}
+	if got != secret {
+		t.Fatalf("SecureGetString(%q) = %q, want %q", key, got, secret)
+	}
+
+	if _, stillSet := os.LookupEnv(key); stillSet {
+		t.Fatalf("%s is still set after SecureGetString", key)
+	}
+
+	// before aliases the backing bytes SecureGetString scrubbed in
+	// place, so it must no longer contain the secret.
+	if strings.Contains(before, secret) {
+		t.Fatalf("captured os.Environ() entry %q still contains the secret after SecureGetString", before)
+	}
+}
@@ -16,6 +16,8 @@ package secureenv
 import (
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"unsafe"
 )
 
@@ -24,9 +26,55 @@ var (
 	uintsize = int(unsafe.Sizeof(uint(0))) * 8
 )
 
+// envLock serializes every lookup-and-unset pair in this package, the
+// same way the Go runtime guards its own copy of the environment in
+// syscall/env_unix.go, so two goroutines racing for the same key cannot
+// both observe the value before either one unsets it.
+var envLock sync.RWMutex
+
+// lookupEnvOrFile is like os.LookupEnv, but when key is not set it also
+// checks key+"_FILE" and, if present, reads the referenced file and uses
+// its trimmed contents as the value. This matches the Docker/Kubernetes
+// secret convention of mounting a secret as a file and pointing to it
+// with a "_FILE" suffixed variable, e.g. DB_PASSWORD_FILE=/run/secrets/db_password.
+// The "_FILE" variable is unset once its file has been read.
+func lookupEnvOrFile(key string) (output string, ok bool) {
+	if env, ok := os.LookupEnv(key); ok {
+		return env, ok
+	}
+
+	fileKey := key + "_FILE"
+	path, ok := os.LookupEnv(fileKey)
+	if !ok {
+		return "", false
+	}
+	defer os.Unsetenv(fileKey)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	defer zero(data)
+	return strings.TrimSpace(string(data)), true
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
 // GetString is same with String, but return the data instead
 func GetString(key string) (output string, ok bool) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+	return getStringLocked(key)
+}
+
+// getStringLocked is GetString's body, callable by other accessors in
+// this package that already hold envLock.
+func getStringLocked(key string) (output string, ok bool) {
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return env, ok
 	}
@@ -34,6 +82,16 @@ func GetString(key string) (output string, ok bool) {
 	return env, ok
 }
 
+// Once calls fn with the value of key exactly once across any number of
+// goroutines calling Once concurrently for the same key: only the
+// goroutine that wins the race to observe key set will have its fn
+// invoked, the rest see it already unset and do nothing.
+func Once(key string, fn func(string)) {
+	if val, ok := GetString(key); ok {
+		fn(val)
+	}
+}
+
 // String set data pointed by output.
 func String(output *string, key string) {
 	val, ok := GetString(key)
@@ -44,7 +102,10 @@ func String(output *string, key string) {
 
 // GetBool is same with Bool, but return the data instead
 func GetBool(key string) (output bool, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return false, ok, nil
 	}
@@ -70,7 +131,10 @@ func Bool(output *bool, key string) error {
 
 // GetFloat64 is same with Float64, but return the data instead
 func GetFloat64(key string) (output float64, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return 0, ok, nil
 	}
@@ -96,7 +160,10 @@ func Float64(output *float64, key string) error {
 
 // GetFloat32 is same with Float32, but return the data instead
 func GetFloat32(key string) (output float32, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return 0, ok, nil
 	}
@@ -122,7 +189,10 @@ func Float32(output *float32, key string) error {
 
 // GetInt64 is same with Int64, but return the data instead
 func GetInt64(key string) (output int64, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return 0, ok, nil
 	}
@@ -148,7 +218,10 @@ func Int64(output *int64, key string) error {
 
 // GetInt32 is same with Int32, but return the data instead
 func GetInt32(key string) (output int32, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return 0, ok, nil
 	}
@@ -174,7 +247,10 @@ func Int32(output *int32, key string) error {
 
 // GetInt is same with Int, but return the data instead
 func GetInt(key string) (output int, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return 0, ok, nil
 	}
@@ -200,7 +276,10 @@ func Int(output *int, key string) error {
 
 // GetUint64 is same with Uint64, but return the data instead
 func GetUint64(key string) (output uint64, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return 0, ok, nil
 	}
@@ -226,7 +305,10 @@ func Uint64(output *uint64, key string) error {
 
 // GetUint32 is same with Uint32, but return the data instead
 func GetUint32(key string) (output uint32, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return 0, ok, nil
 	}
@@ -252,7 +334,10 @@ func Uint32(output *uint32, key string) error {
 
 // GetUint is same with Uint, but return the data instead
 func GetUint(key string) (output uint, ok bool, err error) {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	env, ok := lookupEnvOrFile(key)
 	if !ok {
 		return 0, ok, nil
 	}
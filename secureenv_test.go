@@ -0,0 +1,35 @@
+package secureenv
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnceExactlyOneWinner(t *testing.T) {
+	const key = "SECUREENV_TEST_ONCE"
+	const n = 20
+
+	os.Setenv(key, "value")
+
+	var wins int32
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			Once(key, func(string) {
+				atomic.AddInt32(&wins, 1)
+			})
+		}()
+	}
+	wg.Wait()
+
+	if wins != 1 {
+		t.Fatalf("Once invoked fn %d times across %d goroutines, want exactly 1", wins, n)
+	}
+	if _, ok := os.LookupEnv(key); ok {
+		t.Fatalf("%s is still set after Once", key)
+	}
+}
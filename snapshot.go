@@ -0,0 +1,59 @@
+package secureenv
+
+import (
+	"os"
+	"strings"
+)
+
+// Snapshot atomically copies the entire current environment into the
+// returned map and clears it, so that a subprocess spawned afterwards
+// (via os/exec, cgo, plugins) cannot inherit any of it. This is a
+// stronger, one-shot version of the per-key guarantee the rest of this
+// package provides: an application can read all of its configuration at
+// startup through the returned map and let it be garbage collected once
+// that config has been parsed, since the map is the sole reference to
+// those values left.
+func Snapshot() map[string]string {
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	out := environToMap(os.Environ())
+	os.Clearenv()
+	return out
+}
+
+// SnapshotPrefix is like Snapshot, but only captures and clears the
+// variables whose name starts with prefix.
+func SnapshotPrefix(prefix string) map[string]string {
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	out := make(map[string]string)
+	for _, kv := range os.Environ() {
+		key, value, ok := splitEnv(kv)
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		out[key] = value
+		os.Unsetenv(key)
+	}
+	return out
+}
+
+func environToMap(environ []string) map[string]string {
+	out := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if key, value, ok := splitEnv(kv); ok {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func splitEnv(kv string) (key, value string, ok bool) {
+	i := strings.IndexByte(kv, '=')
+	if i < 0 {
+		return "", "", false
+	}
+	return kv[:i], kv[i+1:], true
+}
@@ -10,14 +10,41 @@ package secureenv
 
 import (
 	"os"
+	"strings"
+	"sync"
 )
 
+// envLock makes the lookup-and-unset pair in Get atomic, so two
+// goroutines racing for the same key cannot both observe the value
+// before either one unsets it.
+var envLock sync.RWMutex
+
 // Get env based on key, if the env not set then return def
+//
+// If key is not set but key+"_FILE" is, the file it points to is read
+// and its trimmed contents are used as the value instead; this matches
+// the Docker/Kubernetes convention of mounting a secret as a file and
+// pointing to it with a "_FILE" suffixed variable. The "_FILE" variable
+// is unset once its file has been read.
 func Get(key, def string) string {
-	env, ok := os.LookupEnv(key)
+	envLock.Lock()
+	defer envLock.Unlock()
+
+	if env, ok := os.LookupEnv(key); ok {
+		os.Unsetenv(key)
+		return env
+	}
+
+	fileKey := key + "_FILE"
+	path, ok := os.LookupEnv(fileKey)
 	if !ok {
 		return def
 	}
-	os.Unsetenv(key)
-	return env
+	os.Unsetenv(fileKey)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return def
+	}
+	return strings.TrimSpace(string(data))
 }